@@ -0,0 +1,128 @@
+package vllm
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"regexp"
+	"syscall"
+
+	"gopkg.in/yaml.v3"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/llm"
+)
+
+// SamplingDefaults holds default sampling parameters for a model entry,
+// as understood by vLLM's OpenAI-compatible server. A nil/empty field
+// means "let vLLM use its own default".
+type SamplingDefaults struct {
+	Temperature *float32 `yaml:"temperature,omitempty"`
+	TopP        *float32 `yaml:"top_p,omitempty"`
+	MaxTokens   *int     `yaml:"max_tokens,omitempty"`
+	Stop        []string `yaml:"stop,omitempty"`
+	GuidedJSON  string   `yaml:"guided_json,omitempty"`
+}
+
+// modelEntry is one model registration in a YAML config file.
+type modelEntry struct {
+	Name             string `yaml:"name"`
+	BaseURL          string `yaml:"base_url"`
+	APIKey           string `yaml:"api_key"`
+	Model            string `yaml:"model"`
+	SamplingDefaults `yaml:",inline"`
+}
+
+// configFile is the top-level shape of a YAML model registry file.
+type configFile struct {
+	Models []modelEntry `yaml:"models"`
+}
+
+// envExpansion matches ${VAR}-style references in config strings.
+var envExpansion = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv replaces ${VAR} references with the value of the
+// corresponding environment variable, leaving the reference untouched if
+// the variable isn't set.
+func expandEnv(s string) string {
+	return envExpansion.ReplaceAllStringFunc(s, func(ref string) string {
+		name := ref[2 : len(ref)-1]
+		if v, ok := os.LookupEnv(name); ok {
+			return v
+		}
+		return ref
+	})
+}
+
+// LoadConfig reads a YAML model registry file from disk, describing one
+// or more named vLLM backends, and registers each one via
+// model.RegisterLLM. This lets operators run one ADK binary against many
+// vLLM backends without recompiling. api_key fields support ${ENV}
+// expansion.
+func LoadConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("vllm: read config %q: %w", path, err)
+	}
+	return loadConfigBytes(data)
+}
+
+// LoadConfigFS is like LoadConfig but reads path from fsys, e.g. an
+// embed.FS bundled into the binary.
+func LoadConfigFS(fsys fs.FS, path string) error {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return fmt.Errorf("vllm: read config %q: %w", path, err)
+	}
+	return loadConfigBytes(data)
+}
+
+func loadConfigBytes(data []byte) error {
+	var cfg configFile
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("vllm: parse config: %w", err)
+	}
+
+	for _, e := range cfg.Models {
+		if e.Name == "" {
+			return fmt.Errorf("vllm: config entry missing name")
+		}
+		entry := e // capture per-iteration copy for the closure below
+		model.RegisterLLM(entry.Name, func() llm.Client {
+			return &Client{
+				BaseURL:  expandEnv(entry.BaseURL),
+				Model:    entry.Model,
+				APIKey:   expandEnv(entry.APIKey),
+				Defaults: entry.SamplingDefaults,
+			}
+		})
+	}
+	return nil
+}
+
+// WatchConfig loads path immediately and again every time the process
+// receives SIGHUP, until ctx is done. Call it in its own goroutine to
+// watch in the background; a failed reload is logged to stderr and does
+// not stop the watch, so a bad edit can be fixed and re-signaled.
+func WatchConfig(ctx context.Context, path string) error {
+	if err := LoadConfig(path); err != nil {
+		return err
+	}
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-sighup:
+			if err := LoadConfig(path); err != nil {
+				fmt.Fprintf(os.Stderr, "vllm: reload %q failed: %v\n", path, err)
+			}
+		}
+	}
+}
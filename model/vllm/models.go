@@ -0,0 +1,101 @@
+package vllm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	openai "github.com/sashabaranov/go-openai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/llm"
+)
+
+// ListModels discovers the models currently served by vLLM via its
+// OpenAI-compatible /v1/models endpoint.
+func (c *Client) ListModels(ctx context.Context) ([]llm.ModelInfo, error) {
+	oa, err := c.newOpenAIClient()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := oa.ListModels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("vllm: list models: %w", err)
+	}
+
+	out := make([]llm.ModelInfo, 0, len(list.Models))
+	for _, m := range list.Models {
+		out = append(out, llm.ModelInfo{
+			ID:      m.ID,
+			OwnedBy: m.OwnedBy,
+			Created: m.CreatedAt,
+		})
+	}
+	return out, nil
+}
+
+// ErrModelNotLoaded is returned by ensureModelAvailable when c.Model is
+// confirmed absent from the server's /v1/models listing. It is a
+// distinct type (rather than a plain fmt.Errorf) so that Router can
+// recognize it as deterministic for this Client and not penalize the
+// endpoint's health or fail over to another one over it.
+type ErrModelNotLoaded struct {
+	Model   string
+	BaseURL string
+}
+
+func (e *ErrModelNotLoaded) Error() string {
+	return fmt.Sprintf("vllm: model %q is not loaded on server %q", e.Model, e.BaseURL)
+}
+
+// ensureModelAvailable validates c.Model against the server's
+// /v1/models listing the first time it's called, and is a no-op on
+// every call after a successful validation. A discovery failure (e.g.
+// the server doesn't expose /v1/models) is not itself treated as fatal;
+// only a confirmed absence of c.Model from the listing is.
+func (c *Client) ensureModelAvailable(ctx context.Context) error {
+	c.mu.Lock()
+	validated := c.modelValidated
+	c.mu.Unlock()
+	if validated {
+		return nil
+	}
+
+	models, err := c.ListModels(ctx)
+	if err != nil {
+		return nil
+	}
+
+	for _, m := range models {
+		if m.ID == c.Model {
+			c.mu.Lock()
+			c.modelValidated = true
+			c.mu.Unlock()
+			return nil
+		}
+	}
+	return &ErrModelNotLoaded{Model: c.Model, BaseURL: c.BaseURL}
+}
+
+// RegisterAll discovers every model served at baseURL and registers each
+// one under its own ID via model.RegisterLLM, so a caller doesn't need to
+// know a vLLM deployment's served model names ahead of time.
+func RegisterAll(ctx context.Context, baseURL, apiKey string) error {
+	cfg := openai.DefaultConfig(apiKey)
+	cfg.BaseURL = strings.TrimRight(baseURL, "/") + "/v1"
+	oa := openai.NewClientWithConfig(cfg)
+
+	list, err := oa.ListModels(ctx)
+	if err != nil {
+		return fmt.Errorf("vllm: discover models at %q: %w", baseURL, err)
+	}
+
+	for _, m := range list.Models {
+		name := m.ID
+		model.RegisterLLM(name, func() llm.Client {
+			return &Client{BaseURL: baseURL, Model: name, APIKey: apiKey}
+		})
+	}
+	return nil
+}
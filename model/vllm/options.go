@@ -0,0 +1,108 @@
+package vllm
+
+import (
+	"encoding/json"
+	"fmt"
+
+	openai "github.com/sashabaranov/go-openai"
+
+	"google.golang.org/adk/model/llm"
+)
+
+// Options holds vLLM-specific sampling and guided-decoding parameters
+// that go-openai's ChatCompletionRequest doesn't expose natively. vLLM's
+// OpenAI-compatible server accepts them as extra top-level fields
+// alongside the standard Chat Completions request, so they're merged in
+// by mergeOptions rather than sent through go-openai directly.
+type Options struct {
+	GuidedJSON    string   `json:"guided_json,omitempty"`
+	GuidedRegex   string   `json:"guided_regex,omitempty"`
+	GuidedChoice  []string `json:"guided_choice,omitempty"`
+	GuidedGrammar string   `json:"guided_grammar,omitempty"`
+
+	MinP              *float32 `json:"min_p,omitempty"`
+	TopK              *int     `json:"top_k,omitempty"`
+	RepetitionPenalty *float32 `json:"repetition_penalty,omitempty"`
+	BestOf            *int     `json:"best_of,omitempty"`
+	UseBeamSearch     bool     `json:"use_beam_search,omitempty"`
+	PromptLogprobs    *int     `json:"prompt_logprobs,omitempty"`
+}
+
+// optionsMetadataKey is the llm.ChatRequest.Metadata key under which a
+// per-call Options override is carried.
+const optionsMetadataKey = "vllm.options"
+
+// WithOptions returns a copy of metadata with opts set as the per-call
+// Options override, for use as llm.ChatRequest.Metadata:
+//
+//	req.Metadata = vllm.WithOptions(req.Metadata, vllm.Options{GuidedJSON: schema})
+func WithOptions(metadata map[string]any, opts Options) map[string]any {
+	out := make(map[string]any, len(metadata)+1)
+	for k, v := range metadata {
+		out[k] = v
+	}
+	out[optionsMetadataKey] = opts
+	return out
+}
+
+// optionsFor resolves the Options to use for req: the per-call override
+// from req.Metadata if present, otherwise c's own default Options.
+func optionsFor(c *Client, req *llm.ChatRequest) Options {
+	if req != nil && req.Metadata != nil {
+		if v, ok := req.Metadata[optionsMetadataKey]; ok {
+			if opts, ok := v.(Options); ok {
+				return opts
+			}
+		}
+	}
+	return c.Options
+}
+
+// mergeOptions marshals base and overlays defaults' Temperature, TopP,
+// MaxTokens and Stop and opts' non-zero fields onto it as extra top-level
+// JSON fields, producing the raw request body vLLM expects.
+// defaults.GuidedJSON is not read here; buildRequestBody folds it into
+// opts beforehand. defaults' scalars are written into the
+// merged map directly rather than set on base beforehand: go-openai tags
+// Temperature/TopP/etc. with `omitempty`, which would silently drop an
+// explicitly-configured zero value (e.g. temperature: 0 for deterministic
+// decoding). This round-trip through map[string]any is necessary because
+// go-openai's ChatCompletionRequest has no extension point for fields it
+// doesn't know about.
+func mergeOptions(base openai.ChatCompletionRequest, defaults SamplingDefaults, opts Options) ([]byte, error) {
+	baseJSON, err := json.Marshal(base)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	var merged map[string]any
+	if err := json.Unmarshal(baseJSON, &merged); err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	if defaults.Temperature != nil {
+		merged["temperature"] = *defaults.Temperature
+	}
+	if defaults.TopP != nil {
+		merged["top_p"] = *defaults.TopP
+	}
+	if defaults.MaxTokens != nil {
+		merged["max_tokens"] = *defaults.MaxTokens
+	}
+	if len(defaults.Stop) > 0 {
+		merged["stop"] = defaults.Stop
+	}
+
+	optJSON, err := json.Marshal(opts)
+	if err != nil {
+		return nil, fmt.Errorf("marshal options: %w", err)
+	}
+	var optMap map[string]any
+	if err := json.Unmarshal(optJSON, &optMap); err != nil {
+		return nil, fmt.Errorf("marshal options: %w", err)
+	}
+	for k, v := range optMap {
+		merged[k] = v
+	}
+
+	return json.Marshal(merged)
+}
@@ -0,0 +1,382 @@
+package vllm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+
+	"google.golang.org/adk/model/llm"
+)
+
+// Strategy selects how a Router picks among its healthy endpoints.
+type Strategy int
+
+const (
+	// RoundRobin cycles through healthy endpoints in order.
+	RoundRobin Strategy = iota
+	// LeastInFlight sends the request to the healthy endpoint currently
+	// handling the fewest in-flight Router.Chat calls. Streams opened via
+	// Router.ChatStream are not counted.
+	LeastInFlight
+	// WeightedRandom picks a healthy endpoint at random, weighted by each
+	// Endpoint's Weight.
+	WeightedRandom
+)
+
+// defaultCooldown is how long an endpoint is skipped after a transient
+// failure (5xx, connection refused) before Router will retry it. An error
+// matching context.DeadlineExceeded does not start a cooldown.
+const defaultCooldown = 30 * time.Second
+
+// ErrNoHealthyEndpoint is returned when every endpoint in a Router's pool
+// is unhealthy or permanently unauthorized.
+var ErrNoHealthyEndpoint = errors.New("vllm: no healthy endpoint available")
+
+// Endpoint describes one backend in a Router's pool.
+type Endpoint struct {
+	// Client talks to this replica/model.
+	Client *Client
+	// Weight is used by the WeightedRandom strategy; ignored otherwise.
+	// Endpoints with Weight <= 0 default to 1.
+	Weight int
+}
+
+// endpointState tracks the health and load of one Endpoint across requests.
+type endpointState struct {
+	endpoint Endpoint
+
+	mu             sync.Mutex
+	inFlight       int
+	unhealthyUntil time.Time
+	unauthorized   bool
+}
+
+func (s *endpointState) healthy(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.unauthorized {
+		return false
+	}
+	return now.After(s.unhealthyUntil)
+}
+
+// markUnhealthy takes the endpoint out of rotation for cooldown, for
+// transient failures (5xx, connection refused).
+func (s *endpointState) markUnhealthy(cooldown time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unhealthyUntil = time.Now().Add(cooldown)
+}
+
+// markUnauthorized takes the endpoint out of rotation permanently, until
+// the Router is reconfigured with a fresh Endpoint for it.
+func (s *endpointState) markUnauthorized() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unauthorized = true
+}
+
+func (s *endpointState) enter() {
+	s.mu.Lock()
+	s.inFlight++
+	s.mu.Unlock()
+}
+
+func (s *endpointState) leave() {
+	s.mu.Lock()
+	s.inFlight--
+	s.mu.Unlock()
+}
+
+func (s *endpointState) load() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.inFlight
+}
+
+// Router fans requests out across a pool of vLLM endpoints, tracking the
+// health of each one and falling back to the next healthy peer on
+// transient failure. It implements llm.ChatClient and
+// llm.StreamingChatClient so an agents.LlmAgent can be configured against
+// the whole pool exactly as it would a single Client.
+type Router struct {
+	strategy Strategy
+	cooldown time.Duration
+
+	mu       sync.Mutex
+	rrCursor int
+	states   []*endpointState
+}
+
+var (
+	_ llm.ChatClient          = (*Router)(nil)
+	_ llm.StreamingChatClient = (*Router)(nil)
+)
+
+// NewRouter builds a Router over the given endpoints using strategy to
+// pick among the healthy ones. It panics if endpoints is empty, since a
+// router with no backends can never serve a request.
+func NewRouter(strategy Strategy, endpoints ...Endpoint) *Router {
+	if len(endpoints) == 0 {
+		panic("vllm: NewRouter requires at least one endpoint")
+	}
+	states := make([]*endpointState, 0, len(endpoints))
+	for _, e := range endpoints {
+		if e.Weight <= 0 {
+			e.Weight = 1
+		}
+		states = append(states, &endpointState{endpoint: e})
+	}
+	return &Router{
+		strategy: strategy,
+		cooldown: defaultCooldown,
+		states:   states,
+	}
+}
+
+// SetCooldown overrides the default unhealthy cooldown window.
+func (r *Router) SetCooldown(d time.Duration) {
+	r.cooldown = d
+}
+
+// candidates returns the healthy endpoints in the order Router should try
+// them, so callers can fail over without re-selecting.
+func (r *Router) candidates() []*endpointState {
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var healthy []*endpointState
+	for _, s := range r.states {
+		if s.healthy(now) {
+			healthy = append(healthy, s)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+
+	switch r.strategy {
+	case LeastInFlight:
+		ordered := append([]*endpointState(nil), healthy...)
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return ordered[i].load() < ordered[j].load()
+		})
+		return ordered
+	case WeightedRandom:
+		return weightedOrder(healthy)
+	default: // RoundRobin
+		start := r.rrCursor % len(healthy)
+		r.rrCursor++
+		return append(append([]*endpointState(nil), healthy[start:]...), healthy[:start]...)
+	}
+}
+
+// weightedOrder picks a first endpoint at random, weighted by Weight, and
+// appends the rest as fallbacks in their original order.
+func weightedOrder(states []*endpointState) []*endpointState {
+	total := 0
+	for _, s := range states {
+		total += s.endpoint.Weight
+	}
+	if total <= 0 {
+		return states
+	}
+
+	pick := rand.Intn(total)
+	for i, s := range states {
+		pick -= s.endpoint.Weight
+		if pick < 0 {
+			ordered := make([]*endpointState, 0, len(states))
+			ordered = append(ordered, s)
+			ordered = append(ordered, states[:i]...)
+			ordered = append(ordered, states[i+1:]...)
+			return ordered
+		}
+	}
+	return states
+}
+
+// isNonFailoverErr reports whether err is one that trying another
+// endpoint can't fix, so Router should surface it to the caller
+// immediately instead of fanning the request out across the rest of
+// the pool. This covers the caller's own context being canceled or
+// timing out, a Client-side ErrModelNotLoaded (deterministic for that
+// Client, not the endpoint), and a deterministic (non-5xx, non-auth)
+// *openai.APIError such as a 400/404/422 for a malformed or rejected
+// request, which will fail identically everywhere.
+func isNonFailoverErr(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var notLoaded *ErrModelNotLoaded
+	if errors.As(err, &notLoaded) {
+		return true
+	}
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.HTTPStatusCode == http.StatusUnauthorized, apiErr.HTTPStatusCode == http.StatusForbidden:
+			return false
+		case apiErr.HTTPStatusCode >= http.StatusInternalServerError:
+			return false
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// recordFailure classifies err and marks s unhealthy or unauthorized
+// accordingly. Connection-level failures such as connection refused
+// aren't wrapped as an *openai.APIError and are treated as transient.
+// Callers must check isNonFailoverErr first; recordFailure only
+// distinguishes auth failures from transient ones. An error matching
+// context.DeadlineExceeded never reaches recordFailure, since
+// isNonFailoverErr reports it as non-failover: the endpoint is neither
+// marked unhealthy nor skipped in favor of the next peer.
+func (r *Router) recordFailure(s *endpointState, err error) {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.HTTPStatusCode == http.StatusUnauthorized, apiErr.HTTPStatusCode == http.StatusForbidden:
+			s.markUnauthorized()
+		case apiErr.HTTPStatusCode >= http.StatusInternalServerError:
+			s.markUnhealthy(r.cooldown)
+		}
+		return
+	}
+	s.markUnhealthy(r.cooldown)
+}
+
+// Chat implements llm.ChatClient.Chat, trying each healthy endpoint in
+// turn until one succeeds.
+func (r *Router) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	states := r.candidates()
+	if len(states) == 0 {
+		return nil, ErrNoHealthyEndpoint
+	}
+
+	var lastErr error
+	for _, s := range states {
+		s.enter()
+		resp, err := s.endpoint.Client.Chat(ctx, req)
+		s.leave()
+		if err == nil {
+			return resp, nil
+		}
+		if isNonFailoverErr(err) {
+			// Trying another endpoint can't fix this: the caller's own
+			// context died, the model is confirmed absent, or the
+			// request itself is deterministically rejected. Surface it
+			// without penalizing the endpoint or fanning out further.
+			return nil, err
+		}
+		lastErr = err
+		r.recordFailure(s, err)
+	}
+	return nil, fmt.Errorf("vllm: all endpoints failed, last error: %w", lastErr)
+}
+
+// ChatStream implements llm.StreamingChatClient.ChatStream. It fails over
+// to the next healthy endpoint if the stream can't be opened, or if it
+// errors before any delta has been emitted to the caller; once a delta
+// has been emitted, errors are surfaced as-is to avoid double-writing
+// output from two endpoints.
+func (r *Router) ChatStream(ctx context.Context, req *llm.ChatRequest) (llm.ChatStream, error) {
+	states := r.candidates()
+	if len(states) == 0 {
+		return nil, ErrNoHealthyEndpoint
+	}
+
+	rs := &routedStream{router: r, ctx: ctx, req: req, candidates: states}
+	if err := rs.open(); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// routedStream wraps a single endpoint's llm.ChatStream and transparently
+// reopens it against the next candidate endpoint on a pre-first-delta
+// failure.
+type routedStream struct {
+	router     *Router
+	ctx        context.Context
+	req        *llm.ChatRequest
+	candidates []*endpointState
+
+	cur     *endpointState
+	stream  llm.ChatStream
+	emitted bool
+}
+
+var _ llm.ChatStream = (*routedStream)(nil)
+
+// open tries candidates in order until one starts streaming.
+func (rs *routedStream) open() error {
+	var lastErr error
+	for len(rs.candidates) > 0 {
+		s := rs.candidates[0]
+		rs.candidates = rs.candidates[1:]
+
+		stream, err := s.endpoint.Client.ChatStream(rs.ctx, rs.req)
+		if err != nil {
+			if isNonFailoverErr(err) {
+				return err
+			}
+			lastErr = err
+			rs.router.recordFailure(s, err)
+			continue
+		}
+		rs.cur = s
+		rs.stream = stream
+		return nil
+	}
+	if lastErr == nil {
+		lastErr = ErrNoHealthyEndpoint
+	}
+	return fmt.Errorf("vllm: all endpoints failed to start stream, last error: %w", lastErr)
+}
+
+func (rs *routedStream) Recv() (*llm.ChatResponse, error) {
+	for {
+		resp, err := rs.stream.Recv()
+		if err == nil {
+			if resp != nil {
+				// A nil response (keep-alive comment, role-only chunk)
+				// hasn't reached the caller as content yet, so it must
+				// not block failover on a later error.
+				rs.emitted = true
+			}
+			return resp, nil
+		}
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if rs.emitted || isNonFailoverErr(err) {
+			return nil, err
+		}
+
+		rs.router.recordFailure(rs.cur, err)
+		_ = rs.stream.Close()
+		if openErr := rs.open(); openErr != nil {
+			return nil, openErr
+		}
+	}
+}
+
+func (rs *routedStream) Close() error {
+	if rs.stream == nil {
+		return nil
+	}
+	return rs.stream.Close()
+}
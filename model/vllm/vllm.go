@@ -3,9 +3,15 @@
 package vllm
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
+	"strings"
+	"sync"
 
 	openai "github.com/sashabaranov/go-openai"
 
@@ -25,8 +31,33 @@ type Client struct {
 	// APIKey is forwarded as Bearer token; vLLM commonly uses a dummy key.
 	APIKey string
 
-	// oa is lazily constructed; if non-nil, it is used directly.
+	// Defaults holds sampling parameters applied to every request against
+	// this Client. Temperature, TopP, MaxTokens and Stop are always sent
+	// when set; GuidedJSON is sent only when the Options in effect for the
+	// call leave GuidedJSON empty. The zero value means "let vLLM use its
+	// own defaults". Populated automatically for models registered via
+	// LoadConfig.
+	Defaults SamplingDefaults
+
+	// Options holds vLLM-specific sampling and guided-decoding parameters
+	// applied to every request unless overridden per-call via WithOptions.
+	Options Options
+
+	// ValidateModel, if true, makes Chat confirm Model is present in the
+	// server's /v1/models listing before the first request (see
+	// ensureModelAvailable). It defaults to false: the extra round trip
+	// is unnecessary for a server known to be configured correctly, and
+	// a confirmed-absent model surfaces as an ErrModelNotLoaded the
+	// caller can otherwise only discover from a failed chat completion.
+	ValidateModel bool
+
+	// oa is lazily constructed; if non-nil, it is used directly. It backs
+	// ListModels, which has no need for the raw-body handling Chat and
+	// ChatStream require to carry vLLM's extra fields.
 	oa *openai.Client
+
+	mu             sync.Mutex
+	modelValidated bool
 }
 
 var (
@@ -56,72 +87,227 @@ func (c *Client) newOpenAIClient() (*openai.Client, error) {
 	return c.oa, nil
 }
 
+// toOpenAIMessages converts llm.Message entries (including tool-role
+// messages and assistant tool calls) into go-openai's message shape.
+func toOpenAIMessages(in []llm.Message) []openai.ChatCompletionMessage {
+	out := make([]openai.ChatCompletionMessage, 0, len(in))
+	for _, m := range in {
+		msg := openai.ChatCompletionMessage{
+			Role:    string(m.Role),
+			Content: m.Content,
+		}
+		if m.Role == llm.RoleTool {
+			msg.ToolCallID = m.ToolCallID
+		}
+		if len(m.ToolCalls) > 0 {
+			msg.ToolCalls = toOpenAIToolCalls(m.ToolCalls)
+		}
+		out = append(out, msg)
+	}
+	return out
+}
+
+// toOpenAITools converts the ADK tool definitions carried on
+// llm.ChatRequest into go-openai's function-calling tool shape.
+func toOpenAITools(in []llm.Tool) []openai.Tool {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]openai.Tool, 0, len(in))
+	for _, t := range in {
+		out = append(out, openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.Parameters,
+			},
+		})
+	}
+	return out
+}
+
+// toOpenAIToolCalls converts llm.ToolCall entries (e.g. an assistant's
+// prior tool calls being replayed into history) into go-openai's shape.
+func toOpenAIToolCalls(in []llm.ToolCall) []openai.ToolCall {
+	out := make([]openai.ToolCall, 0, len(in))
+	for _, tc := range in {
+		out = append(out, openai.ToolCall{
+			ID:   tc.ID,
+			Type: openai.ToolTypeFunction,
+			Function: openai.FunctionCall{
+				Name:      tc.Name,
+				Arguments: tc.Arguments,
+			},
+		})
+	}
+	return out
+}
+
+// fromOpenAIToolCalls converts go-openai tool calls back into the
+// llm.ToolCall shape surfaced to the agent.
+func fromOpenAIToolCalls(in []openai.ToolCall) []llm.ToolCall {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]llm.ToolCall, 0, len(in))
+	for _, tc := range in {
+		out = append(out, llm.ToolCall{
+			ID:        tc.ID,
+			Name:      tc.Function.Name,
+			Arguments: tc.Function.Arguments,
+		})
+	}
+	return out
+}
+
+// buildRequestBody assembles the outgoing JSON request body for req,
+// applying c.Defaults to the standard Chat Completions fields and
+// merging in whichever Options apply (the per-call override carried on
+// req.Metadata, or c.Options otherwise) as extra top-level JSON fields.
+func (c *Client) buildRequestBody(req *llm.ChatRequest, stream bool) ([]byte, error) {
+	base := openai.ChatCompletionRequest{
+		Model:      c.Model,
+		Messages:   toOpenAIMessages(req.Messages),
+		Tools:      toOpenAITools(req.Tools),
+		ToolChoice: req.ToolChoice,
+		Stream:     stream,
+	}
+	if stream {
+		// Ask vLLM to emit a final chunk carrying token usage, since it
+		// otherwise only reports usage on non-streaming responses.
+		base.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+	}
+
+	opts := optionsFor(c, req)
+	if opts.GuidedJSON == "" {
+		opts.GuidedJSON = c.Defaults.GuidedJSON
+	}
+
+	// c.Defaults' scalars are merged directly into the JSON body by
+	// mergeOptions rather than set on base above, so an explicit zero
+	// value (e.g. temperature: 0) isn't lost to go-openai's `omitempty`
+	// struct tags.
+	return mergeOptions(base, c.Defaults, opts)
+}
+
+// endpoint returns the Chat Completions URL for c.
+func (c *Client) endpoint() string {
+	return strings.TrimRight(c.BaseURL, "/") + "/v1/chat/completions"
+}
+
+// post issues the raw Chat Completions request. A raw *http.Response is
+// returned (rather than going through go-openai) because go-openai's
+// ChatCompletionRequest has no extension point for vLLM's extra sampling
+// and guided-decoding fields merged in by buildRequestBody.
+func (c *Client) post(ctx context.Context, body []byte, stream bool) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+	if stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	}
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return nil, &openai.APIError{
+			HTTPStatusCode: resp.StatusCode,
+			Message:        string(data),
+		}
+	}
+	return resp, nil
+}
+
 // Chat implements llm.ChatClient.Chat using the OpenAI Chat Completions API.
 // It sends the messages from llm.ChatRequest and returns the first choice
-// as an llm.ChatResponse.
+// as an llm.ChatResponse, including any tool calls, finish reason, token
+// usage, and logprobs the server reported.
 func (c *Client) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
-	oa, err := c.newOpenAIClient()
-	if err != nil {
+	if _, err := c.newOpenAIClient(); err != nil {
 		return nil, err
 	}
+	if c.ValidateModel {
+		if err := c.ensureModelAvailable(ctx); err != nil {
+			return nil, err
+		}
+	}
 
-	messages := make([]openai.ChatCompletionMessage, 0, len(req.Messages))
-	for _, m := range req.Messages {
-		messages = append(messages, openai.ChatCompletionMessage{
-			Role:    string(m.Role),
-			Content: m.Content,
-		})
+	body, err := c.buildRequestBody(req, false)
+	if err != nil {
+		return nil, fmt.Errorf("vllm chat error: %w", err)
 	}
 
-	resp, err := oa.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-		Model:    c.Model,
-		Messages: messages,
-		Stream:   false,
-	})
+	httpResp, err := c.post(ctx, body, false)
 	if err != nil {
 		return nil, fmt.Errorf("vllm chat error: %w", err)
 	}
+	defer httpResp.Body.Close()
+
+	var resp openai.ChatCompletionResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("vllm chat error: decode response: %w", err)
+	}
 
 	if len(resp.Choices) == 0 {
 		return nil, fmt.Errorf("vllm: no choices in response")
 	}
 
-	msg := resp.Choices[0].Message
-	return &llm.ChatResponse{
+	choice := resp.Choices[0]
+	chatResp := &llm.ChatResponse{
 		Message: llm.Message{
-			Role:    llm.Role(msg.Role),
-			Content: msg.Content,
+			Role:      llm.Role(choice.Message.Role),
+			Content:   choice.Message.Content,
+			ToolCalls: fromOpenAIToolCalls(choice.Message.ToolCalls),
 		},
-	}, nil
+		ToolCalls:    fromOpenAIToolCalls(choice.Message.ToolCalls),
+		FinishReason: string(choice.FinishReason),
+		Usage: llm.TokenUsage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+		},
+	}
+	if choice.LogProbs != nil {
+		if raw, err := json.Marshal(choice.LogProbs); err == nil {
+			chatResp.Logprobs = raw
+		}
+	}
+	return chatResp, nil
 }
 
-// ChatStream implements llm.StreamingChatClient.ChatStream using the
-// OpenAI streaming Chat Completions API. It returns an llm.ChatStream
-// that surfaces incremental deltas as ChatResponses.
+// ChatStream implements llm.StreamingChatClient.ChatStream. It returns an
+// llm.ChatStream that parses the server's SSE response directly, which is
+// necessary to carry the same vLLM-specific request fields Chat does.
 func (c *Client) ChatStream(ctx context.Context, req *llm.ChatRequest) (llm.ChatStream, error) {
-	oa, err := c.newOpenAIClient()
-	if err != nil {
+	if _, err := c.newOpenAIClient(); err != nil {
 		return nil, err
 	}
 
-	messages := make([]openai.ChatCompletionMessage, 0, len(req.Messages))
-	for _, m := range req.Messages {
-		messages = append(messages, openai.ChatCompletionMessage{
-			Role:    string(m.Role),
-			Content: m.Content,
-		})
+	body, err := c.buildRequestBody(req, true)
+	if err != nil {
+		return nil, fmt.Errorf("vllm stream error: %w", err)
 	}
 
-	stream, err := oa.CreateChatCompletionStream(ctx, openai.ChatCompletionRequest{
-		Model:    c.Model,
-		Messages: messages,
-		Stream:   true,
-	})
+	httpResp, err := c.post(ctx, body, true)
 	if err != nil {
 		return nil, fmt.Errorf("vllm stream error: %w", err)
 	}
 
-	return &streamWrapper{stream: stream}, nil
+	return &streamWrapper{
+		body:      httpResp.Body,
+		reader:    bufio.NewReader(httpResp.Body),
+		toolCalls: make(map[int]*llm.ToolCall),
+	}, nil
 }
 
 // Name (optional) â€“ if llm.Client in your version exposes Name(), you
@@ -131,20 +317,38 @@ func (c *Client) Name() string {
 	return c.Model
 }
 
-// streamWrapper adapts go-openai's ChatCompletionStream to llm.ChatStream.
+// streamWrapper adapts vLLM's raw SSE Chat Completions stream to
+// llm.ChatStream.
 type streamWrapper struct {
-	stream *openai.ChatCompletionStream
+	body   io.ReadCloser
+	reader *bufio.Reader
+
+	// toolCalls accumulates in-progress tool call fragments keyed by their
+	// delta index, since vLLM (like OpenAI) streams a tool call's name and
+	// arguments across multiple chunks.
+	toolCalls map[int]*llm.ToolCall
+	// toolCallOrder preserves the order in which tool call indices first
+	// appeared so the aggregated calls come out in a stable order.
+	toolCallOrder []int
 }
 
 var _ llm.ChatStream = (*streamWrapper)(nil)
 
-// Recv reads the next delta from the streaming response. It returns:
+// Recv reads the next SSE event from the stream and returns the delta it
+// carries. It returns:
 //   - (*llm.ChatResponse, nil) on a non-empty content delta
-//   - (nil, nil) on a non-content chunk (e.g., role-only)
+//   - (*llm.ChatResponse, nil) with ToolCalls populated once a tool call
+//     finishes streaming
+//   - (*llm.ChatResponse, nil) with only Usage populated, for the final
+//     usage-only chunk sent when StreamOptions.IncludeUsage is set
+//   - (*llm.ChatResponse, nil) with FinishReason and an assistant Role but
+//     no content, for a normal completion's empty-content terminal chunk
+//   - (nil, nil) on a chunk with nothing to surface yet (e.g., role-only,
+//     a tool-call fragment, a blank keep-alive line, or an SSE comment)
 //   - (nil, io.EOF) when the stream is done
 //   - (nil, err) on error
 func (s *streamWrapper) Recv() (*llm.ChatResponse, error) {
-	chunk, err := s.stream.Recv()
+	line, err := s.reader.ReadString('\n')
 	if err != nil {
 		if err == io.EOF {
 			return nil, io.EOF
@@ -152,14 +356,89 @@ func (s *streamWrapper) Recv() (*llm.ChatResponse, error) {
 		return nil, err
 	}
 
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" || !strings.HasPrefix(line, "data:") {
+		// Blank keep-alive line or an SSE comment/field we don't use;
+		// let the caller loop again.
+		return nil, nil
+	}
+
+	payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+	if payload == "[DONE]" {
+		return nil, io.EOF
+	}
+
+	var chunk openai.ChatCompletionStreamResponse
+	if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+		return nil, fmt.Errorf("vllm: decode stream chunk: %w", err)
+	}
+
 	if len(chunk.Choices) == 0 {
+		if chunk.Usage != nil {
+			// The final chunk of a stream opted into
+			// StreamOptions.IncludeUsage carries no choices, only usage.
+			return &llm.ChatResponse{
+				Usage: llm.TokenUsage{
+					PromptTokens:     chunk.Usage.PromptTokens,
+					CompletionTokens: chunk.Usage.CompletionTokens,
+					TotalTokens:      chunk.Usage.TotalTokens,
+				},
+			}, nil
+		}
 		// No usable delta in this chunk; let caller loop again.
 		return nil, nil
 	}
 
-	delta := chunk.Choices[0].Delta
+	choice := chunk.Choices[0]
+	delta := choice.Delta
+
+	for _, tc := range delta.ToolCalls {
+		idx := 0
+		if tc.Index != nil {
+			idx = *tc.Index
+		}
+		existing, ok := s.toolCalls[idx]
+		if !ok {
+			existing = &llm.ToolCall{}
+			s.toolCalls[idx] = existing
+			s.toolCallOrder = append(s.toolCallOrder, idx)
+		}
+		if tc.ID != "" {
+			existing.ID = tc.ID
+		}
+		if tc.Function.Name != "" {
+			existing.Name = tc.Function.Name
+		}
+		existing.Arguments += tc.Function.Arguments
+	}
+
+	if choice.FinishReason == openai.FinishReasonToolCalls {
+		calls := make([]llm.ToolCall, 0, len(s.toolCallOrder))
+		for _, idx := range s.toolCallOrder {
+			calls = append(calls, *s.toolCalls[idx])
+		}
+		return &llm.ChatResponse{
+			Message: llm.Message{
+				Role:      llm.RoleAssistant,
+				ToolCalls: calls,
+			},
+			ToolCalls:    calls,
+			FinishReason: string(choice.FinishReason),
+		}, nil
+	}
+
 	if delta.Content == "" {
-		// Could just be a role or other metadata.
+		if choice.FinishReason != "" {
+			// The terminal chunk of a normal completion carries no
+			// content, only the finish reason; don't drop it.
+			return &llm.ChatResponse{
+				Message: llm.Message{
+					Role: llm.RoleAssistant,
+				},
+				FinishReason: string(choice.FinishReason),
+			}, nil
+		}
+		// Could just be a role, tool-call fragment, or other metadata.
 		return nil, nil
 	}
 
@@ -168,10 +447,11 @@ func (s *streamWrapper) Recv() (*llm.ChatResponse, error) {
 			Role:    llm.RoleAssistant,
 			Content: delta.Content,
 		},
+		FinishReason: string(choice.FinishReason),
 	}, nil
 }
 
-// Close closes the underlying stream.
+// Close closes the underlying HTTP response body.
 func (s *streamWrapper) Close() error {
-	return s.stream.Close()
+	return s.body.Close()
 }
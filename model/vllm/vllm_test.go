@@ -170,8 +170,132 @@ func TestChatStream_Error(t *testing.T) {
 		Messages: []llm.Message{{Role: llm.RoleUser, Content: "hi"}},
 	})
 	if err == nil {
-		// go-openai returns errors synchronously on CreateChatCompletionStream
+		// A non-2xx status is surfaced synchronously, before any SSE
+		// framing is involved.
 		t.Fatalf("expected error from ChatStream, got nil and stream=%#v", stream)
 	}
 }
 
+// TestChatStream_SSEFraming drives streamWrapper.Recv against raw SSE
+// byte sequences shaped like real vLLM output: "data: ...\n\n" framing,
+// ": ping" keep-alive comments, a final Choices=[]/Usage-populated chunk,
+// and writes that split a single SSE event across multiple Write calls.
+func TestChatStream_SSEFraming(t *testing.T) {
+	tests := []struct {
+		name             string
+		writes           []string
+		wantText         string
+		wantUsage        llm.TokenUsage
+		wantFinishReason string
+		wantChunks       int // number of non-nil Recv results before EOF
+	}{
+		{
+			name: "double newline framing with keep-alive comments",
+			writes: []string{
+				": ping\n\n",
+				"data: ", `{"choices":[{"delta":{"content":"hello "}}]}`, "\n\n",
+				": ping\n\n",
+				"data: ", `{"choices":[{"delta":{"content":"world"}}]}`, "\n\n",
+				"data: [DONE]\n\n",
+			},
+			wantText:   "hello world",
+			wantChunks: 2,
+		},
+		{
+			name: "event split across writes",
+			writes: []string{
+				"data: {\"choices\":[{\"delta\":",
+				"{\"content\":\"hel", "lo\"}}]}\n\n",
+				"data: [DONE]\n\n",
+			},
+			wantText:   "hello",
+			wantChunks: 1,
+		},
+		{
+			name: "final usage-only chunk with empty choices",
+			writes: []string{
+				"data: ", `{"choices":[{"delta":{"content":"hi"}}]}`, "\n\n",
+				"data: ", `{"choices":[],"usage":{"prompt_tokens":3,"completion_tokens":1,"total_tokens":4}}`, "\n\n",
+				"data: [DONE]\n\n",
+			},
+			wantText:   "hi",
+			wantUsage:  llm.TokenUsage{PromptTokens: 3, CompletionTokens: 1, TotalTokens: 4},
+			wantChunks: 2,
+		},
+		{
+			name: "empty-content terminal chunk carries finish reason",
+			writes: []string{
+				"data: ", `{"choices":[{"delta":{"content":"hi"}}]}`, "\n\n",
+				"data: ", `{"choices":[{"delta":{},"finish_reason":"stop"}]}`, "\n\n",
+				"data: [DONE]\n\n",
+			},
+			wantText:         "hi",
+			wantFinishReason: "stop",
+			wantChunks:       2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				flusher, _ := w.(http.Flusher)
+				for _, chunk := range tt.writes {
+					_, _ = io.WriteString(w, chunk)
+					if flusher != nil {
+						flusher.Flush()
+					}
+				}
+			})
+
+			stream, err := client.ChatStream(context.Background(), &llm.ChatRequest{
+				Messages: []llm.Message{{Role: llm.RoleUser, Content: "stream please"}},
+			})
+			if err != nil {
+				t.Fatalf("ChatStream returned error: %v", err)
+			}
+			defer stream.Close()
+
+			var (
+				text         string
+				usage        llm.TokenUsage
+				finishReason string
+				gotChunks    int
+			)
+			for {
+				resp, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Recv error: %v", err)
+				}
+				if resp == nil {
+					continue
+				}
+				gotChunks++
+				text += resp.Message.Content
+				if resp.Usage != (llm.TokenUsage{}) {
+					usage = resp.Usage
+				}
+				if resp.FinishReason != "" {
+					finishReason = resp.FinishReason
+				}
+			}
+
+			if text != tt.wantText {
+				t.Fatalf("unexpected streamed content: got %q, want %q", text, tt.wantText)
+			}
+			if usage != tt.wantUsage {
+				t.Fatalf("unexpected usage: got %+v, want %+v", usage, tt.wantUsage)
+			}
+			if finishReason != tt.wantFinishReason {
+				t.Fatalf("unexpected finish reason: got %q, want %q", finishReason, tt.wantFinishReason)
+			}
+			if gotChunks != tt.wantChunks {
+				t.Fatalf("unexpected chunk count: got %d, want %d", gotChunks, tt.wantChunks)
+			}
+		})
+	}
+}
+